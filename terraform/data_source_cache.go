@@ -0,0 +1,330 @@
+package terraform
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// DefaultDataSourceCacheDir is where DiskDataSourceCache stores its
+// entries when no other location is configured.
+const DefaultDataSourceCacheDir = ".terraform/datacache"
+
+// DataSourceCacheEntry is what a DataSourceCache stores and retrieves for
+// a single cache key.
+type DataSourceCacheEntry struct {
+	State    *InstanceState `json:"state"`
+	StoredAt time.Time      `json:"stored_at"`
+	TTL      time.Duration  `json:"ttl"`
+}
+
+func (e *DataSourceCacheEntry) expired(now time.Time) bool {
+	if e.TTL <= 0 {
+		return false
+	}
+	return now.Sub(e.StoredAt) > e.TTL
+}
+
+// DataSourceCache is the interface EvalReadDataCache uses to avoid
+// re-reading a data source that hasn't changed since it was last
+// refreshed. Implementations must be safe for concurrent use and must
+// not return an entry whose TTL has passed; the caller builds the keys
+// and does no expiry check of its own.
+type DataSourceCache interface {
+	// Get returns the cached state for key, or ok == false if there is
+	// no usable (i.e. present and unexpired) entry.
+	Get(key string) (state *InstanceState, ok bool, err error)
+
+	// Put stores state under key, to expire after ttl (or never, if ttl
+	// is zero).
+	Put(key string, state *InstanceState, ttl time.Duration) error
+}
+
+// ActiveDataSourceCache is the DataSourceCache consulted by
+// EvalReadDataCache and EvalWriteDataCache. Nothing in this package sets
+// it (ContextOpts has no field for it), so it is assigned directly by
+// callers; a nil value disables caching entirely, which is the default.
+var ActiveDataSourceCache DataSourceCache
+
+// DiskDataSourceCache is the default DataSourceCache, storing one JSON
+// file per cache key under Dir.
+type DiskDataSourceCache struct {
+	Dir string
+
+	mu sync.Mutex
+}
+
+// NewDiskDataSourceCache returns a DiskDataSourceCache rooted at dir,
+// defaulting to DefaultDataSourceCacheDir if dir is empty.
+func NewDiskDataSourceCache(dir string) *DiskDataSourceCache {
+	if dir == "" {
+		dir = DefaultDataSourceCacheDir
+	}
+	return &DiskDataSourceCache{Dir: dir}
+}
+
+func (c *DiskDataSourceCache) path(key string) string {
+	return filepath.Join(c.Dir, key+".json")
+}
+
+func (c *DiskDataSourceCache) Get(key string) (*InstanceState, bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	data, err := ioutil.ReadFile(c.path(key))
+	if os.IsNotExist(err) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+
+	var entry DataSourceCacheEntry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return nil, false, err
+	}
+	if entry.expired(time.Now()) {
+		return nil, false, nil
+	}
+
+	return entry.State, true, nil
+}
+
+func (c *DiskDataSourceCache) Put(key string, state *InstanceState, ttl time.Duration) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err := os.MkdirAll(c.Dir, 0755); err != nil {
+		return err
+	}
+
+	entry := DataSourceCacheEntry{
+		State:    state,
+		StoredAt: time.Now(),
+		TTL:      ttl,
+	}
+	data, err := json.Marshal(&entry)
+	if err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(c.path(key), data, 0644)
+}
+
+// dataSourceCacheKey hashes together everything that should invalidate a
+// cached data source read: its interpolated configuration, the provider
+// it was resolved to, and a fingerprint of the states of the resources it
+// depends on.
+func dataSourceCacheKey(stateId string, config *ResourceConfig, resolvedProvider string, dependencyFingerprint string) string {
+	h := sha256.New()
+	fmt.Fprintf(h, "id=%s\n", stateId)
+	fmt.Fprintf(h, "provider=%s\n", resolvedProvider)
+	fmt.Fprintf(h, "deps=%s\n", dependencyFingerprint)
+	if config != nil {
+		// ResourceConfig.Raw is already the fully-interpolated
+		// key/value map at this point in evaluation, so it's a stable
+		// basis for the hash.
+		if raw, err := json.Marshal(config.Raw); err == nil {
+			h.Write(raw)
+		}
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// dependencyStateFingerprint hashes the current primary attributes of
+// each dependency address, so a cache entry is invalidated as soon as any
+// upstream resource it depends on changes, even if this data source's own
+// config didn't.
+func dependencyStateFingerprint(state *State, dependencies []string) string {
+	h := sha256.New()
+	for _, dep := range dependencies {
+		fmt.Fprintf(h, "dep=%s\n", dep)
+		if state == nil {
+			continue
+		}
+		for _, mod := range state.Modules {
+			rs, ok := mod.Resources[dep]
+			if !ok || rs.Primary == nil {
+				continue
+			}
+			if raw, err := json.Marshal(rs.Primary.Attributes); err == nil {
+				h.Write(raw)
+			}
+		}
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// cacheTTLMetaKey is the key data source configs use, inside a nested
+// "terraform" block (`terraform { cache_ttl = "10m" }`), to override
+// DefaultDataSourceCacheTTL or opt out of caching altogether with
+// cache_ttl = "0".
+//
+// Ideally this would be a real meta-argument parsed in the config
+// package, alongside depends_on and lifecycle, so a terraform{} block
+// would be stripped before EvalValidateResource's provider-schema check
+// ever sees it (the way those are). That parser lives in a part of the
+// config package this tree doesn't include, so for now this reads the
+// block back out of the already-interpolated ResourceConfig instead;
+// revisit once the config-layer parser exists.
+const cacheTTLMetaKey = "cache_ttl"
+
+// DefaultDataSourceCacheTTL is the TTL applied to a cached data source
+// read when its config doesn't set its own cache_ttl. Zero means entries
+// never expire on their own (they're still invalidated by a config or
+// dependency change).
+var DefaultDataSourceCacheTTL time.Duration
+
+// dataSourceCacheTTL inspects a data source's interpolated config for a
+// `terraform { cache_ttl = "..." }` meta-argument, returning the TTL to
+// use and whether caching is disabled entirely for this data source. An
+// unparseable cache_ttl value is returned as an error rather than
+// silently falling back to the default, since that's a config mistake
+// the user needs to see.
+func dataSourceCacheTTL(config *ResourceConfig) (ttl time.Duration, disabled bool, err error) {
+	ttl = DefaultDataSourceCacheTTL
+
+	if config == nil {
+		return ttl, false, nil
+	}
+
+	raw, ok := config.Raw["terraform"]
+	if !ok {
+		return ttl, false, nil
+	}
+
+	// A single nested block like `terraform { ... }` is represented as
+	// either a map or a one-element slice of maps, depending on how it
+	// was flattened on the way in.
+	var block map[string]interface{}
+	switch v := raw.(type) {
+	case map[string]interface{}:
+		block = v
+	case []map[string]interface{}:
+		if len(v) > 0 {
+			block = v[0]
+		}
+	case []interface{}:
+		if len(v) > 0 {
+			block, _ = v[0].(map[string]interface{})
+		}
+	}
+	if block == nil {
+		return ttl, false, nil
+	}
+
+	rawTTL, ok := block[cacheTTLMetaKey]
+	if !ok {
+		return ttl, false, nil
+	}
+
+	s, ok := rawTTL.(string)
+	if !ok {
+		return ttl, false, fmt.Errorf("invalid cache_ttl %#v: must be a string", rawTTL)
+	}
+	if s == "0" {
+		return 0, true, nil
+	}
+
+	parsed, err := time.ParseDuration(s)
+	if err != nil {
+		return 0, false, fmt.Errorf("invalid cache_ttl %q: %s", s, err)
+	}
+	return parsed, false, nil
+}
+
+// EvalReadDataCache looks up a previous read of a data source in
+// ActiveDataSourceCache and, on a hit, writes it directly to Output
+// without going through EvalReadDataDiff/EvalReadDataApply. Hit and miss
+// are reported through the active Hook so operators can observe savings.
+type EvalReadDataCache struct {
+	Info             *InstanceInfo
+	StateId          string
+	Config           **ResourceConfig
+	ResolvedProvider string
+	Dependencies     []string
+
+	Output    **InstanceState
+	OutputHit *bool
+}
+
+// Eval implements EvalNode.
+func (n *EvalReadDataCache) Eval(ctx EvalContext) (interface{}, error) {
+	_, disabled, err := dataSourceCacheTTL(*n.Config)
+	if err != nil {
+		return nil, err
+	}
+	if ActiveDataSourceCache == nil || disabled {
+		if n.OutputHit != nil {
+			*n.OutputHit = false
+		}
+		return nil, nil
+	}
+
+	state, lock := ctx.State()
+	lock.RLock()
+	fingerprint := dependencyStateFingerprint(state, n.Dependencies)
+	lock.RUnlock()
+
+	key := dataSourceCacheKey(n.StateId, *n.Config, n.ResolvedProvider, fingerprint)
+
+	cached, hit, err := ActiveDataSourceCache.Get(key)
+	if err != nil {
+		return nil, err
+	}
+
+	if n.OutputHit != nil {
+		*n.OutputHit = hit
+	}
+
+	ctx.Hook(func(h Hook) (HookAction, error) {
+		return h.DataSourceCacheResult(n.Info, hit)
+	})
+
+	if !hit {
+		return nil, nil
+	}
+
+	if n.Output != nil {
+		*n.Output = cached
+	}
+	return nil, nil
+}
+
+// EvalWriteDataCache stores a freshly-read data source state in
+// ActiveDataSourceCache under the same key EvalReadDataCache would
+// compute for it, so the next refresh can short-circuit.
+type EvalWriteDataCache struct {
+	Info             *InstanceInfo
+	StateId          string
+	Config           **ResourceConfig
+	ResolvedProvider string
+	Dependencies     []string
+
+	State **InstanceState
+}
+
+// Eval implements EvalNode.
+func (n *EvalWriteDataCache) Eval(ctx EvalContext) (interface{}, error) {
+	ttl, disabled, err := dataSourceCacheTTL(*n.Config)
+	if err != nil {
+		return nil, err
+	}
+	if ActiveDataSourceCache == nil || disabled || *n.State == nil {
+		return nil, nil
+	}
+
+	state, lock := ctx.State()
+	lock.RLock()
+	fingerprint := dependencyStateFingerprint(state, n.Dependencies)
+	lock.RUnlock()
+
+	key := dataSourceCacheKey(n.StateId, *n.Config, n.ResolvedProvider, fingerprint)
+	return nil, ActiveDataSourceCache.Put(key, *n.State, ttl)
+}
@@ -0,0 +1,108 @@
+package terraform
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestDiskDataSourceCache_roundTrip(t *testing.T) {
+	c := NewDiskDataSourceCache(filepath.Join(t.TempDir(), "datacache"))
+
+	state := &InstanceState{ID: "abc123"}
+
+	if _, hit, err := c.Get("k1"); err != nil {
+		t.Fatalf("err: %s", err)
+	} else if hit {
+		t.Fatalf("expected a miss before anything is stored")
+	}
+
+	if err := c.Put("k1", state, 0); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	got, hit, err := c.Get("k1")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if !hit {
+		t.Fatalf("expected a hit after Put")
+	}
+	if got.ID != state.ID {
+		t.Fatalf("got %#v, want %#v", got, state)
+	}
+}
+
+func TestDiskDataSourceCache_ttlExpiry(t *testing.T) {
+	c := NewDiskDataSourceCache(filepath.Join(t.TempDir(), "datacache"))
+
+	if err := c.Put("k1", &InstanceState{ID: "abc123"}, time.Millisecond); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	time.Sleep(10 * time.Millisecond)
+
+	if _, hit, err := c.Get("k1"); err != nil {
+		t.Fatalf("err: %s", err)
+	} else if hit {
+		t.Fatalf("expected the entry to have expired")
+	}
+}
+
+func TestDataSourceCacheTTL(t *testing.T) {
+	cases := []struct {
+		name       string
+		config     *ResourceConfig
+		wantTTL    time.Duration
+		wantOptOut bool
+		wantErr    bool
+	}{
+		{
+			name:    "no terraform block uses default",
+			config:  &ResourceConfig{Raw: map[string]interface{}{}},
+			wantTTL: DefaultDataSourceCacheTTL,
+		},
+		{
+			name: "explicit duration",
+			config: &ResourceConfig{Raw: map[string]interface{}{
+				"terraform": map[string]interface{}{"cache_ttl": "10m"},
+			}},
+			wantTTL: 10 * time.Minute,
+		},
+		{
+			name: "opt-out",
+			config: &ResourceConfig{Raw: map[string]interface{}{
+				"terraform": map[string]interface{}{"cache_ttl": "0"},
+			}},
+			wantOptOut: true,
+		},
+		{
+			name: "invalid duration",
+			config: &ResourceConfig{Raw: map[string]interface{}{
+				"terraform": map[string]interface{}{"cache_ttl": "10mn"},
+			}},
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			ttl, disabled, err := dataSourceCacheTTL(tc.config)
+			if tc.wantErr {
+				if err == nil {
+					t.Fatalf("expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("err: %s", err)
+			}
+			if disabled != tc.wantOptOut {
+				t.Fatalf("disabled = %v, want %v", disabled, tc.wantOptOut)
+			}
+			if !tc.wantOptOut && ttl != tc.wantTTL {
+				t.Fatalf("ttl = %s, want %s", ttl, tc.wantTTL)
+			}
+		})
+	}
+}
@@ -0,0 +1,140 @@
+package terraform
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/hashicorp/terraform/config"
+)
+
+// DiffPolicyResult is the outcome a DiffPolicy returns after inspecting a
+// computed InstanceDiff.
+type DiffPolicyResult int
+
+const (
+	// DiffPolicyAllow lets the diff proceed with no further action.
+	DiffPolicyAllow DiffPolicyResult = iota
+
+	// DiffPolicyWarn lets the diff proceed but passes Message to the
+	// active Hook's PolicyWarn method.
+	DiffPolicyWarn
+
+	// DiffPolicyDeny aborts the apply; Message is returned as part of
+	// the resulting error.
+	DiffPolicyDeny
+
+	// DiffPolicyRequireApproval pauses the apply and asks the active
+	// Hook's PolicyApproval method whether to proceed.
+	DiffPolicyRequireApproval
+)
+
+// DiffPolicy inspects the diff computed for a single resource instance
+// and decides whether it should be allowed to proceed. Policies are
+// registered globally with RegisterDiffPolicy and are run, in
+// registration order, by EvalApplyDiffPolicies immediately after
+// EvalDiff computes the instance's InstanceDiff.
+type DiffPolicy interface {
+	// PolicyName identifies the policy in diagnostics and in the
+	// PolicyApproval hook.
+	PolicyName() string
+
+	// Evaluate inspects diff for the resource described by info and r,
+	// returning the policy's decision and a human-readable explanation.
+	// An empty message is fine for DiffPolicyAllow.
+	Evaluate(info *InstanceInfo, r *config.Resource, diff *InstanceDiff) (DiffPolicyResult, string)
+}
+
+var diffPolicies struct {
+	sync.Mutex
+	registered []DiffPolicy
+}
+
+// RegisterDiffPolicy adds a policy to the set consulted by
+// EvalApplyDiffPolicies. Policies run in the order they were registered.
+// This is typically called from an init function in the code that wants
+// to enforce an organization-specific policy.
+func RegisterDiffPolicy(p DiffPolicy) {
+	diffPolicies.Lock()
+	defer diffPolicies.Unlock()
+	diffPolicies.registered = append(diffPolicies.registered, p)
+}
+
+func registeredDiffPolicies() []DiffPolicy {
+	diffPolicies.Lock()
+	defer diffPolicies.Unlock()
+	return append([]DiffPolicy(nil), diffPolicies.registered...)
+}
+
+func init() {
+	RegisterDiffPolicy(&preventDestroyDiffPolicy{})
+}
+
+// preventDestroyDiffPolicy reproduces the historical behavior of
+// EvalCheckPreventDestroy as a DiffPolicy, so that node evaluation goes
+// through a single, extensible policy chain instead of a hard-coded
+// check.
+type preventDestroyDiffPolicy struct{}
+
+func (p *preventDestroyDiffPolicy) PolicyName() string {
+	return "prevent_destroy"
+}
+
+func (p *preventDestroyDiffPolicy) Evaluate(info *InstanceInfo, r *config.Resource, diff *InstanceDiff) (DiffPolicyResult, string) {
+	if diff == nil || !diff.GetDestroy() || r == nil || !r.Lifecycle.PreventDestroy {
+		return DiffPolicyAllow, ""
+	}
+
+	return DiffPolicyDeny, fmt.Sprintf(
+		"%s: the plan would destroy this resource, but it has lifecycle.prevent_destroy "+
+			"set, protecting it from being destroyed.", info.HumanId())
+}
+
+// EvalApplyDiffPolicies runs every registered DiffPolicy against a
+// computed InstanceDiff, in registration order, stopping at the first
+// DiffPolicyDeny or unapproved DiffPolicyRequireApproval.
+type EvalApplyDiffPolicies struct {
+	Info     *InstanceInfo
+	Resource *config.Resource
+	Diff     **InstanceDiff
+}
+
+// Eval implements EvalNode.
+func (n *EvalApplyDiffPolicies) Eval(ctx EvalContext) (interface{}, error) {
+	diff := *n.Diff
+	if diff == nil || diff.Empty() {
+		return nil, nil
+	}
+
+	for _, policy := range registeredDiffPolicies() {
+		result, message := policy.Evaluate(n.Info, n.Resource, diff)
+
+		switch result {
+		case DiffPolicyAllow:
+			continue
+
+		case DiffPolicyWarn:
+			// PolicyWarn is a distinct hook call from PolicyApproval so
+			// a CLI hook can't mistake this for something that needs
+			// approving. Its result is purely informational: whatever
+			// it returns, the diff still proceeds.
+			ctx.Hook(func(h Hook) (HookAction, error) {
+				return h.PolicyWarn(n.Info, policy.PolicyName(), message)
+			})
+
+		case DiffPolicyDeny:
+			return nil, fmt.Errorf("diff rejected by policy %q: %s", policy.PolicyName(), message)
+
+		case DiffPolicyRequireApproval:
+			// h.PolicyApproval returns HookActionHalt when the approval
+			// is declined, which ctx.Hook turns into an error here in
+			// the same way it does for every other hook call.
+			if err := ctx.Hook(func(h Hook) (HookAction, error) {
+				return h.PolicyApproval(n.Info, policy.PolicyName(), message)
+			}); err != nil {
+				return nil, fmt.Errorf("diff for policy %q was not approved: %s", policy.PolicyName(), err)
+			}
+		}
+	}
+
+	return nil, nil
+}
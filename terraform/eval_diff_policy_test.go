@@ -0,0 +1,99 @@
+package terraform
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform/config"
+)
+
+type testDiffPolicy struct {
+	name   string
+	result DiffPolicyResult
+}
+
+func (p *testDiffPolicy) PolicyName() string { return p.name }
+
+func (p *testDiffPolicy) Evaluate(info *InstanceInfo, r *config.Resource, diff *InstanceDiff) (DiffPolicyResult, string) {
+	return p.result, "test policy " + p.name
+}
+
+func withDiffPolicies(t *testing.T, policies []DiffPolicy, f func()) {
+	t.Helper()
+	diffPolicies.Lock()
+	saved := diffPolicies.registered
+	diffPolicies.registered = policies
+	diffPolicies.Unlock()
+
+	defer func() {
+		diffPolicies.Lock()
+		diffPolicies.registered = saved
+		diffPolicies.Unlock()
+	}()
+
+	f()
+}
+
+func TestEvalApplyDiffPolicies_deny(t *testing.T) {
+	withDiffPolicies(t, []DiffPolicy{&testDiffPolicy{name: "deny-all", result: DiffPolicyDeny}}, func() {
+		diff := &InstanceDiff{Destroy: true}
+		n := &EvalApplyDiffPolicies{
+			Info:     &InstanceInfo{Id: "test_resource.foo"},
+			Resource: &config.Resource{},
+			Diff:     &diff,
+		}
+
+		ctx := new(MockEvalContext)
+		if _, err := n.Eval(ctx); err == nil {
+			t.Fatalf("expected an error from a denying policy")
+		}
+	})
+}
+
+func TestEvalApplyDiffPolicies_warnDoesNotHalt(t *testing.T) {
+	withDiffPolicies(t, []DiffPolicy{&testDiffPolicy{name: "warn-all", result: DiffPolicyWarn}}, func() {
+		diff := &InstanceDiff{Destroy: true}
+		n := &EvalApplyDiffPolicies{
+			Info:     &InstanceInfo{Id: "test_resource.foo"},
+			Resource: &config.Resource{},
+			Diff:     &diff,
+		}
+
+		ctx := new(MockEvalContext)
+		ctx.HookHook = &testHaltingHook{}
+
+		if _, err := n.Eval(ctx); err != nil {
+			t.Fatalf("a Warn policy must never halt the apply, got err: %s", err)
+		}
+	})
+}
+
+// testHaltingHook always declines, to prove that a decline from
+// PolicyWarn (unlike PolicyApproval) has no effect on the result.
+type testHaltingHook struct {
+	NilHook
+}
+
+func (h *testHaltingHook) PolicyWarn(info *InstanceInfo, policyName, message string) (HookAction, error) {
+	return HookActionHalt, nil
+}
+
+func (h *testHaltingHook) PolicyApproval(info *InstanceInfo, policyName, message string) (HookAction, error) {
+	return HookActionHalt, nil
+}
+
+func TestEvalApplyDiffPolicies_preventDestroyBuiltin(t *testing.T) {
+	diff := &InstanceDiff{Destroy: true}
+	resource := &config.Resource{}
+	resource.Lifecycle.PreventDestroy = true
+
+	n := &EvalApplyDiffPolicies{
+		Info:     &InstanceInfo{Id: "test_resource.foo"},
+		Resource: resource,
+		Diff:     &diff,
+	}
+
+	ctx := new(MockEvalContext)
+	if _, err := n.Eval(ctx); err == nil {
+		t.Fatalf("expected the built-in prevent_destroy policy to deny this diff")
+	}
+}
@@ -0,0 +1,104 @@
+package terraform
+
+import (
+	"sync"
+	"time"
+)
+
+// providerRateLimiters holds one token bucket per resolved provider
+// address, shared across all EvalRateLimit nodes in a single Context run.
+var providerRateLimiters = struct {
+	sync.Mutex
+	buckets map[string]*tokenBucket
+}{buckets: make(map[string]*tokenBucket)}
+
+// tokenBucket is a minimal token-bucket rate limiter. It refills at a
+// fixed rate and blocks callers until a token is available, which is all
+// EvalRateLimit needs: there's no burst tuning exposed, since the limiter
+// exists to flatten bursts against a provider's API, not to shape them.
+type tokenBucket struct {
+	mu       sync.Mutex
+	rate     float64 // tokens added per second
+	tokens   float64
+	max      float64
+	lastFill time.Time
+}
+
+func newTokenBucket(ratePerSecond float64) *tokenBucket {
+	return &tokenBucket{
+		rate:     ratePerSecond,
+		tokens:   ratePerSecond,
+		max:      ratePerSecond,
+		lastFill: time.Now(),
+	}
+}
+
+func (b *tokenBucket) Take() {
+	for {
+		b.mu.Lock()
+		now := time.Now()
+		elapsed := now.Sub(b.lastFill).Seconds()
+		b.lastFill = now
+		b.tokens += elapsed * b.rate
+		if b.tokens > b.max {
+			b.tokens = b.max
+		}
+
+		if b.tokens >= 1 {
+			b.tokens--
+			b.mu.Unlock()
+			return
+		}
+
+		wait := time.Duration((1 - b.tokens) / b.rate * float64(time.Second))
+		b.mu.Unlock()
+		time.Sleep(wait)
+	}
+}
+
+// ProviderRateLimits maps a resolved provider address to the maximum
+// number of requests per second EvalRateLimit should allow through for
+// that provider. It is meant to be populated once up front, via
+// SetProviderRateLimits, from a ContextOpts field fed by its own CLI flag
+// (e.g. -rate-limit-per-provider=provider=N/sec) — a separate, still
+// unwired flag from -parallelism-per-provider, since a requests/sec cap
+// and a concurrent-requests cap are independent knobs. See the doc
+// comment on ParseParallelismPerProviderFlag in provider_limits_flag.go
+// for the full wiring gap. It is consulted by every EvalRateLimit node
+// for the duration of a Context run.
+var ProviderRateLimits map[string]float64
+
+// EvalRateLimit wraps another EvalNode, most commonly EvalReadDataApply,
+// and blocks until a token is available in the named provider's rate
+// limiter before delegating to it. This bounds requests/sec to a
+// provider independently of ConcurrencyLimitTransformer, which bounds how
+// many requests can be in flight at once: a low concurrency limit with no
+// rate limit can still hammer a provider with fast sequential requests.
+type EvalRateLimit struct {
+	Provider string
+	Inner    EvalNode
+}
+
+var _ EvalNode = (*EvalRateLimit)(nil)
+
+// Eval implements EvalNode.
+func (n *EvalRateLimit) Eval(ctx EvalContext) (interface{}, error) {
+	if limit, ok := ProviderRateLimits[n.Provider]; ok && limit > 0 {
+		bucket := providerBucket(n.Provider, limit)
+		bucket.Take()
+	}
+
+	return n.Inner.Eval(ctx)
+}
+
+func providerBucket(provider string, ratePerSecond float64) *tokenBucket {
+	providerRateLimiters.Lock()
+	defer providerRateLimiters.Unlock()
+
+	b, ok := providerRateLimiters.buckets[provider]
+	if !ok {
+		b = newTokenBucket(ratePerSecond)
+		providerRateLimiters.buckets[provider] = b
+	}
+	return b
+}
@@ -0,0 +1,64 @@
+package terraform
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTokenBucket_withinRate(t *testing.T) {
+	b := newTokenBucket(1000) // 1000/sec, so taking a handful is effectively instant
+
+	start := time.Now()
+	for i := 0; i < 5; i++ {
+		b.Take()
+	}
+	if elapsed := time.Since(start); elapsed > 50*time.Millisecond {
+		t.Fatalf("expected takes within the burst to be near-instant, took %s", elapsed)
+	}
+}
+
+func TestTokenBucket_throttles(t *testing.T) {
+	b := newTokenBucket(10) // 10/sec
+
+	// Drain the initial burst, then the next Take should have to wait
+	// for a refill instead of returning immediately.
+	for i := 0; i < 10; i++ {
+		b.Take()
+	}
+
+	start := time.Now()
+	b.Take()
+	if elapsed := time.Since(start); elapsed < 50*time.Millisecond {
+		t.Fatalf("expected Take to block for a refill, returned after %s", elapsed)
+	}
+}
+
+func TestEvalRateLimit_noLimitConfigured(t *testing.T) {
+	ProviderRateLimits = nil
+
+	called := false
+	n := &EvalRateLimit{
+		Provider: "provider.aws",
+		Inner: &evalNodeTestFunc{fn: func(ctx EvalContext) (interface{}, error) {
+			called = true
+			return nil, nil
+		}},
+	}
+
+	if _, err := n.Eval(nil); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if !called {
+		t.Fatalf("expected inner node to run")
+	}
+}
+
+// evalNodeTestFunc adapts a plain function to EvalNode for tests that
+// only need to assert whether (and with what) the wrapped node ran.
+type evalNodeTestFunc struct {
+	fn func(ctx EvalContext) (interface{}, error)
+}
+
+func (n *evalNodeTestFunc) Eval(ctx EvalContext) (interface{}, error) {
+	return n.fn(ctx)
+}
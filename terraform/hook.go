@@ -0,0 +1,62 @@
+package terraform
+
+// HookAction is an enum of actions that can be taken as a result of a
+// hook callback. This allows hooks to modify some flow of the
+// evaluation, such as stopping early.
+type HookAction byte
+
+const (
+	// HookActionContinue continues the evaluation as normal.
+	HookActionContinue HookAction = iota
+
+	// HookActionHalt stops the evaluation of this particular object,
+	// usually to signal that the caller declined to proceed (for
+	// example, an interactive approval prompt that was rejected).
+	HookActionHalt
+)
+
+// Hook is the interface that must be implemented to hook into various
+// parts of Terraform, allowing you to inspect or change behavior at
+// runtime.
+//
+// This snapshot only carries the methods that this package's DiffPolicy
+// and data source caching code call; a full Hook implementation has many
+// more (PreDiff, PostApply, etc.) that live alongside the rest of the
+// hook machinery elsewhere in the codebase.
+type Hook interface {
+	// PolicyApproval is called when a DiffPolicy returns
+	// DiffPolicyRequireApproval for a resource's diff. Returning
+	// HookActionHalt (or a non-nil error) declines the approval and
+	// aborts the apply of that diff; HookActionContinue approves it.
+	PolicyApproval(info *InstanceInfo, policyName, message string) (HookAction, error)
+
+	// PolicyWarn is called when a DiffPolicy returns DiffPolicyWarn for
+	// a resource's diff. Unlike PolicyApproval, its result never blocks
+	// the apply — it exists purely so a hook can surface the message.
+	PolicyWarn(info *InstanceInfo, policyName, message string) (HookAction, error)
+
+	// DataSourceCacheResult is called after EvalReadDataCache looks up a
+	// data source in the active DataSourceCache, reporting whether the
+	// lookup was a hit or a miss so operators can observe cache
+	// effectiveness.
+	DataSourceCacheResult(info *InstanceInfo, hit bool) (HookAction, error)
+}
+
+// NilHook is a Hook implementation that does nothing. It can be embedded
+// in other Hook implementations that only care about a subset of the
+// methods, so they don't have to stub out the rest themselves.
+type NilHook struct{}
+
+var _ Hook = (*NilHook)(nil)
+
+func (*NilHook) PolicyApproval(*InstanceInfo, string, string) (HookAction, error) {
+	return HookActionContinue, nil
+}
+
+func (*NilHook) PolicyWarn(*InstanceInfo, string, string) (HookAction, error) {
+	return HookActionContinue, nil
+}
+
+func (*NilHook) DataSourceCacheResult(*InstanceInfo, bool) (HookAction, error) {
+	return HookActionContinue, nil
+}
@@ -87,6 +87,11 @@ func (n *NodeRefreshableDataResource) DynamicExpand(ctx EvalContext) (*Graph, er
 		// Connect references so ordering is correct
 		&ReferenceTransformer{},
 
+		// Bound how many instances of a single provider can be refreshed
+		// concurrently, so an account with hundreds of data resources
+		// against one provider doesn't trigger upstream throttling.
+		&ConcurrencyLimitTransformer{Limits: ProviderConcurrencyLimits},
+
 		// Make sure there is a single root
 		&RootTransformer{},
 	}
@@ -154,6 +159,7 @@ func (n *NodeRefreshableDataResourceInstance) EvalTree() EvalNode {
 	var diff *InstanceDiff
 	var provider ResourceProvider
 	var state *InstanceState
+	var cacheHit bool
 
 	return &EvalSequence{
 		Nodes: []EvalNode{
@@ -208,19 +214,54 @@ func (n *NodeRefreshableDataResourceInstance) EvalTree() EvalNode {
 				Output: &provider,
 			},
 
-			&EvalReadDataDiff{
-				Info:        info,
-				Config:      &config,
-				Provider:    &provider,
-				Output:      &diff,
-				OutputState: &state,
+			// Check whether we already have a fresh read of this data
+			// source cached from a previous run. A hit writes straight
+			// to state, skipping the diff+apply below entirely.
+			&EvalReadDataCache{
+				Info:             info,
+				StateId:          stateId,
+				Config:           &config,
+				ResolvedProvider: n.ResolvedProvider,
+				Dependencies:     rs.Dependencies,
+				Output:           &state,
+				OutputHit:        &cacheHit,
 			},
 
-			&EvalReadDataApply{
-				Info:     info,
-				Diff:     &diff,
-				Provider: &provider,
-				Output:   &state,
+			&EvalIf{
+				If: func(ctx EvalContext) (bool, error) {
+					return !cacheHit, nil
+				},
+
+				Then: &EvalSequence{
+					Nodes: []EvalNode{
+						&EvalReadDataDiff{
+							Info:        info,
+							Config:      &config,
+							Provider:    &provider,
+							Output:      &diff,
+							OutputState: &state,
+						},
+
+						&EvalRateLimit{
+							Provider: n.ResolvedProvider,
+							Inner: &EvalReadDataApply{
+								Info:     info,
+								Diff:     &diff,
+								Provider: &provider,
+								Output:   &state,
+							},
+						},
+
+						&EvalWriteDataCache{
+							Info:             info,
+							StateId:          stateId,
+							Config:           &config,
+							ResolvedProvider: n.ResolvedProvider,
+							Dependencies:     rs.Dependencies,
+							State:            &state,
+						},
+					},
+				},
 			},
 
 			&EvalWriteState{
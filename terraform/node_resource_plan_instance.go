@@ -10,6 +10,15 @@ import (
 // NodePlannableResourceInstance represents a _single_ resource
 // instance that is plannable. This means this represents a single
 // count index, for example.
+//
+// Note: managed-resource reads are not concurrency-limited today.
+// ConcurrencyLimitTransformer (transform_concurrency_limit.go) only runs
+// from NodeRefreshableDataResource.DynamicExpand, which expands data
+// resources; the equivalent expansion step for a managed resource
+// (NodeApplyableResource or similar, producing one
+// NodePlannableResourceInstance per count index) isn't present in this
+// package's files, so there's nowhere to add the same transformer step
+// for managed resources yet.
 type NodePlannableResourceInstance struct {
 	*NodeAbstractResourceInstance
 }
@@ -155,7 +164,12 @@ func (n *NodePlannableResourceInstance) evalTreeManagedResource(addr addrs.AbsRe
 				OutputDiff:  &diff,
 				OutputState: &state,
 			},
-			&EvalCheckPreventDestroy{
+			// Run the registered DiffPolicy chain (which includes a
+			// built-in policy reproducing the historical
+			// lifecycle.prevent_destroy check) against the computed
+			// diff, in place of a single hard-coded gate.
+			&EvalApplyDiffPolicies{
+				Info:     info,
 				Resource: n.Config,
 				Diff:     &diff,
 			},
@@ -0,0 +1,65 @@
+package terraform
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ParseParallelismPerProviderFlag parses one repetition of the CLI's
+// `-parallelism-per-provider=provider=N` flag (for example
+// "aws=10" or "google.eu=2") into a provider address and its
+// concurrency limit.
+//
+// This only covers the parsing itself. Wiring the flag all the way
+// through still needs two things, neither of which is present here:
+//   - command/meta.go (or wherever the apply/plan/refresh commands build
+//     their flag.FlagSet) needs a `flag.Var` entry that accumulates
+//     repeated -parallelism-per-provider values via this function into a
+//     map[string]int.
+//   - ContextOpts (in context.go) needs a ParallelismPerProvider
+//     map[string]int field that NewContext copies into
+//     ProviderConcurrencyLimits, the same way it already threads
+//     Parallelism into the walker's global semaphore.
+//
+// Until that follow-up lands, ProviderConcurrencyLimits (and
+// ProviderRateLimits, which needs the equivalent -rate-limit-per-provider
+// flag and ContextOpts field) must be set directly by callers that embed
+// this package, rather than from the terraform CLI.
+func ParseParallelismPerProviderFlag(raw string) (provider string, limit int, err error) {
+	idx := strings.LastIndex(raw, "=")
+	if idx < 0 {
+		return "", 0, fmt.Errorf("invalid -parallelism-per-provider value %q: expected provider=N", raw)
+	}
+
+	provider = raw[:idx]
+	if provider == "" {
+		return "", 0, fmt.Errorf("invalid -parallelism-per-provider value %q: missing provider", raw)
+	}
+
+	limit, err = strconv.Atoi(raw[idx+1:])
+	if err != nil {
+		return "", 0, fmt.Errorf("invalid -parallelism-per-provider value %q: %s", raw, err)
+	}
+	if limit <= 0 {
+		return "", 0, fmt.Errorf("invalid -parallelism-per-provider value %q: N must be positive", raw)
+	}
+
+	return provider, limit, nil
+}
+
+// SetProviderConcurrencyLimits replaces ProviderConcurrencyLimits. It is
+// the seam ContextOpts should call into once it grows the
+// ParallelismPerProvider field described above; exposing it as a function
+// rather than having callers assign the package variable directly keeps
+// that seam explicit.
+func SetProviderConcurrencyLimits(limits map[string]int) {
+	ProviderConcurrencyLimits = limits
+}
+
+// SetProviderRateLimits replaces ProviderRateLimits. See
+// SetProviderConcurrencyLimits; this is the equivalent seam for the
+// requests/sec limiter.
+func SetProviderRateLimits(limits map[string]float64) {
+	ProviderRateLimits = limits
+}
@@ -0,0 +1,96 @@
+package terraform
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/terraform/dag"
+)
+
+// DefaultParallelismPerProvider is the concurrency limit applied to a
+// provider that has no entry in ConcurrencyLimitTransformer.Limits. Zero
+// means "unbounded", which preserves the historical behavior of relying
+// entirely on the walker's global parallelism.
+const DefaultParallelismPerProvider = 0
+
+// ProviderConcurrencyLimits maps a resolved provider address to the
+// maximum number of that provider's data resource instances that may be
+// refreshed concurrently, and is consulted by every
+// ConcurrencyLimitTransformer for the duration of a Context run. Nothing
+// in this package fills it from the -parallelism-per-provider flag or
+// from ContextOpts; it holds whatever SetProviderConcurrencyLimits (or a
+// direct assignment) last stored, and is nil otherwise.
+var ProviderConcurrencyLimits map[string]int
+
+// ConcurrencyLimitTransformer bounds how many instances of a given
+// resolved provider can be evaluated concurrently, independent of the
+// walker's overall parallelism. It is intended to run late in a dynamic
+// expansion, after the graph has been expanded to one vertex per resource
+// instance, so it can see the full set of instances sharing a provider.
+//
+// Rather than introducing a runtime semaphore (which the generic walker
+// has no way to associate with a subset of vertices), this transformer
+// enforces the limit structurally: for each provider whose instance count
+// exceeds its configured limit N, it chains each instance to the instance
+// N positions before it. That chain ensures no more than N instances of
+// that provider are ever "in flight" at once, since the (i)th instance
+// cannot start until the (i-N)th has completed.
+type ConcurrencyLimitTransformer struct {
+	// Limits maps a resolved provider address (as returned by
+	// GraphNodeProviderConsumer.ProvidedBy, or the ResolvedProvider field
+	// on resource nodes) to the maximum number of concurrent evaluations
+	// permitted for that provider. Providers with no entry are left
+	// unbounded.
+	Limits map[string]int
+}
+
+// graphNodeConcurrencyLimited is implemented by vertices that
+// ConcurrencyLimitTransformer knows how to group and bound.
+type graphNodeConcurrencyLimited interface {
+	dag.Vertex
+
+	// concurrencyLimitProvider returns the resolved provider address
+	// this vertex will read or write through.
+	concurrencyLimitProvider() string
+}
+
+func (n *NodeRefreshableDataResourceInstance) concurrencyLimitProvider() string {
+	return n.ResolvedProvider
+}
+
+func (t *ConcurrencyLimitTransformer) Transform(g *Graph) error {
+	if len(t.Limits) == 0 {
+		return nil
+	}
+
+	byProvider := make(map[string][]graphNodeConcurrencyLimited)
+	for _, v := range g.Vertices() {
+		limited, ok := v.(graphNodeConcurrencyLimited)
+		if !ok {
+			continue
+		}
+		provider := limited.concurrencyLimitProvider()
+		if provider == "" {
+			continue
+		}
+		byProvider[provider] = append(byProvider[provider], limited)
+	}
+
+	for provider, vertices := range byProvider {
+		limit := t.Limits[provider]
+		if limit <= 0 || len(vertices) <= limit {
+			continue
+		}
+
+		for i := limit; i < len(vertices); i++ {
+			g.Connect(dag.BasicEdge(vertices[i], vertices[i-limit]))
+		}
+	}
+
+	return nil
+}
+
+// GoString is used to make ConcurrencyLimitTransformer's limits visible in
+// graph debug dumps.
+func (t *ConcurrencyLimitTransformer) GoString() string {
+	return fmt.Sprintf("*terraform.ConcurrencyLimitTransformer{Limits: %#v}", t.Limits)
+}
@@ -0,0 +1,134 @@
+package terraform
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform/dag"
+)
+
+type testConcurrencyLimitedNode struct {
+	name     string
+	provider string
+}
+
+func (n *testConcurrencyLimitedNode) Name() string                     { return n.name }
+func (n *testConcurrencyLimitedNode) concurrencyLimitProvider() string { return n.provider }
+
+func TestConcurrencyLimitTransformer(t *testing.T) {
+	g := &Graph{}
+	var nodes []dag.Vertex
+	for i := 0; i < 5; i++ {
+		n := &testConcurrencyLimitedNode{name: "aws_instance.foo", provider: "provider.aws"}
+		nodes = append(nodes, n)
+		g.Add(n)
+	}
+
+	limit := 2
+	tf := &ConcurrencyLimitTransformer{Limits: map[string]int{"provider.aws": limit}}
+	if err := tf.Transform(g); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	// The transformer groups a provider's vertices by whatever order it
+	// iterates the graph's internal set in, which Go doesn't guarantee
+	// is stable across runs. So rather than asserting a specific vertex
+	// chained to a specific other vertex, assert the properties that
+	// must hold regardless of that ordering: the number of chaining
+	// edges added, and that a simulated walk never runs more than
+	// `limit` of these vertices at once.
+	if got, want := len(g.Edges()), len(nodes)-limit; got != want {
+		t.Fatalf("got %d edges, want %d (len(nodes) - limit)", got, want)
+	}
+
+	if max := maxConcurrentInWalk(t, g, nodes); max > limit {
+		t.Fatalf("simulated walk ran %d vertices concurrently, want at most %d", max, limit)
+	}
+}
+
+func TestConcurrencyLimitTransformer_underLimit(t *testing.T) {
+	g := &Graph{}
+	var nodes []dag.Vertex
+	for i := 0; i < 2; i++ {
+		n := &testConcurrencyLimitedNode{name: "aws_instance.foo", provider: "provider.aws"}
+		nodes = append(nodes, n)
+		g.Add(n)
+	}
+
+	tf := &ConcurrencyLimitTransformer{Limits: map[string]int{"provider.aws": 5}}
+	if err := tf.Transform(g); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	if got := len(g.Edges()); got != 0 {
+		t.Fatalf("expected no chaining edges when the group is within the limit, got %d", got)
+	}
+}
+
+func TestConcurrencyLimitTransformer_noLimit(t *testing.T) {
+	g := &Graph{}
+	n1 := &testConcurrencyLimitedNode{name: "a", provider: "provider.aws"}
+	n2 := &testConcurrencyLimitedNode{name: "b", provider: "provider.aws"}
+	g.Add(n1)
+	g.Add(n2)
+
+	tf := &ConcurrencyLimitTransformer{}
+	if err := tf.Transform(g); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	if got := len(g.Edges()); got != 0 {
+		t.Fatalf("expected no edges when no limits are configured, got %d", got)
+	}
+}
+
+// maxConcurrentInWalk simulates a walker that runs every vertex with no
+// outstanding dependencies in one "round", then repeats with whatever
+// becomes ready next, and returns the largest round size seen. This is
+// the property ConcurrencyLimitTransformer actually needs to hold,
+// independent of which specific vertices it happened to chain together.
+func maxConcurrentInWalk(t *testing.T, g *Graph, vertices []dag.Vertex) int {
+	t.Helper()
+
+	remaining := make(map[dag.Vertex]bool)
+	for _, v := range vertices {
+		remaining[v] = true
+	}
+
+	deps := make(map[dag.Vertex]map[dag.Vertex]bool)
+	for _, e := range g.Edges() {
+		src := e.Source()
+		if deps[src] == nil {
+			deps[src] = make(map[dag.Vertex]bool)
+		}
+		deps[src][e.Target()] = true
+	}
+
+	max := 0
+	for len(remaining) > 0 {
+		var ready []dag.Vertex
+		for v := range remaining {
+			blocked := false
+			for dep := range deps[v] {
+				if remaining[dep] {
+					blocked = true
+					break
+				}
+			}
+			if !blocked {
+				ready = append(ready, v)
+			}
+		}
+
+		if len(ready) == 0 {
+			t.Fatalf("no vertex is ready; the chain has a cycle")
+		}
+		if len(ready) > max {
+			max = len(ready)
+		}
+		for _, v := range ready {
+			delete(remaining, v)
+		}
+	}
+
+	return max
+}